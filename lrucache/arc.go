@@ -0,0 +1,310 @@
+package lrucache
+
+import (
+	"sync"
+	"time"
+)
+
+// arcListID identifies which of ARC's four lists an entry currently belongs
+// to. B1 and B2 are "ghost" lists: the entries they hold carry no value,
+// only a key, recording that it was recently evicted.
+type arcListID int
+
+const (
+	arcT1 arcListID = iota
+	arcT2
+	arcB1
+	arcB2
+)
+
+type arcEntry struct {
+	key, value string
+	expiry     time.Time
+	list       arcListID
+	prev, next *arcEntry
+}
+
+func (e *arcEntry) IsExpired() bool {
+	if e.expiry.IsZero() {
+		return false
+	}
+	return time.Now().After(e.expiry)
+}
+
+// arcList is an LRU-ordered doubly-linked list, most-recently-used at the
+// head. All four of T1, T2, B1 and B2 are shaped this way.
+type arcList struct {
+	head, tail arcEntry
+	n          int
+}
+
+func (l *arcList) init() {
+	l.head.next = &l.tail
+	l.tail.prev = &l.head
+	l.n = 0
+}
+
+func (l *arcList) pushFront(e *arcEntry) {
+	e.prev = &l.head
+	e.next = l.head.next
+	l.head.next.prev = e
+	l.head.next = e
+	l.n++
+}
+
+func (l *arcList) unlink(e *arcEntry) {
+	e.prev.next = e.next
+	e.next.prev = e.prev
+	l.n--
+}
+
+func (l *arcList) lru() *arcEntry {
+	if l.n == 0 {
+		return nil
+	}
+	return l.tail.prev
+}
+
+// arcCache implements LRUCache using the Adaptive Replacement Cache (ARC)
+// algorithm. It maintains two LRU lists of live entries - T1 for items seen
+// once, T2 for items seen at least twice - and two ghost lists of evicted
+// keys, B1 and B2, which it uses to adaptively grow or shrink the target
+// size, p, of T1 depending on whether recency or frequency is winning.
+type arcCache struct {
+	sync.Mutex
+
+	maxSize int
+	ttl     time.Duration
+	p       int
+
+	t1, t2, b1, b2 arcList
+	m              map[string]*arcEntry
+}
+
+// NewARC returns a new LRUCache using the ARC eviction algorithm, adaptively
+// balancing between recency and frequency based on ghost list hit patterns.
+// If maxSize is greater than zero, the cache holds at most maxSize entries.
+// If the given ttl is greater than zero, entries retrieved after this limit
+// are evicted and not returned.
+func NewARC(maxSize int, ttl time.Duration) LRUCache {
+	if maxSize < 0 {
+		panic("invalid max size")
+	}
+	if ttl < 0 {
+		panic("invalid ttl")
+	}
+	c := &arcCache{
+		maxSize: maxSize,
+		ttl:     ttl,
+		m:       make(map[string]*arcEntry),
+	}
+	c.t1.init()
+	c.t2.init()
+	c.b1.init()
+	c.b2.init()
+	return c
+}
+
+func (c *arcCache) refreshExpiry(e *arcEntry) {
+	if c.ttl > 0 {
+		e.expiry = time.Now().Add(c.ttl)
+	}
+}
+
+func (c *arcCache) Put(key, value string) {
+	c.Lock()
+	defer c.Unlock()
+
+	if e, ok := c.m[key]; ok {
+		switch e.list {
+		case arcT1, arcT2:
+			c.promote(e, value)
+			return
+		case arcB1:
+			if c.maxSize > 0 {
+				c.b1.unlink(e)
+				c.p = minInt(c.maxSize, c.p+maxInt(1, c.b2.n/maxInt(1, c.b1.n)))
+				c.replace(false)
+			} else {
+				c.b1.unlink(e)
+			}
+			c.promote(e, value)
+			return
+		case arcB2:
+			if c.maxSize > 0 {
+				c.b2.unlink(e)
+				c.p = maxInt(0, c.p-maxInt(1, c.b1.n/maxInt(1, c.b2.n)))
+				c.replace(true)
+			} else {
+				c.b2.unlink(e)
+			}
+			c.promote(e, value)
+			return
+		}
+	}
+
+	if c.maxSize > 0 {
+		switch {
+		case c.t1.n+c.b1.n == c.maxSize:
+			if c.t1.n < c.maxSize {
+				c.dropGhost(&c.b1)
+				c.replace(false)
+			} else {
+				lru := c.t1.lru()
+				c.t1.unlink(lru)
+				delete(c.m, lru.key)
+			}
+		case c.t1.n+c.b1.n < c.maxSize && c.t1.n+c.b1.n+c.t2.n+c.b2.n >= c.maxSize:
+			if c.t1.n+c.b1.n+c.t2.n+c.b2.n == 2*c.maxSize {
+				c.dropGhost(&c.b2)
+			}
+			c.replace(false)
+		}
+	}
+
+	e := &arcEntry{key: key, value: value, list: arcT1}
+	c.refreshExpiry(e)
+	c.m[key] = e
+	c.t1.pushFront(e)
+}
+
+// promote moves a live or resurrected-from-ghost entry to the MRU position
+// of T2, since any second sighting of a key - whether still cached or only
+// remembered as a ghost - marks it as frequently, not just recently, used.
+func (c *arcCache) promote(e *arcEntry, value string) {
+	if e.list == arcT1 {
+		c.t1.unlink(e)
+	} else if e.list == arcT2 {
+		c.t2.unlink(e)
+	}
+	e.value = value
+	e.list = arcT2
+	c.refreshExpiry(e)
+	c.t2.pushFront(e)
+}
+
+// replace evicts the LRU entry of T1 or T2 into the corresponding ghost
+// list, per the ARC paper's REPLACE(x, p) procedure. xInB2 is true only when
+// replace is invoked on behalf of a ghost hit in B2.
+func (c *arcCache) replace(xInB2 bool) {
+	if c.t1.n > 0 && (c.t1.n > c.p || (xInB2 && c.t1.n == c.p)) {
+		lru := c.t1.lru()
+		c.t1.unlink(lru)
+		lru.list = arcB1
+		lru.value = ""
+		c.b1.pushFront(lru)
+		return
+	}
+	lru := c.t2.lru()
+	if lru == nil {
+		return
+	}
+	c.t2.unlink(lru)
+	lru.list = arcB2
+	lru.value = ""
+	c.b2.pushFront(lru)
+}
+
+// dropGhost permanently forgets the LRU entry of a ghost list, used when T1
+// and B1 together would otherwise exceed maxSize, or all four lists together
+// would otherwise exceed 2*maxSize.
+func (c *arcCache) dropGhost(l *arcList) {
+	lru := l.lru()
+	if lru == nil {
+		return
+	}
+	l.unlink(lru)
+	delete(c.m, lru.key)
+}
+
+func (c *arcCache) Get(key string) (value string, ok bool) {
+	c.Lock()
+	defer c.Unlock()
+
+	e, exists := c.m[key]
+	if !exists {
+		return
+	}
+
+	switch e.list {
+	case arcT1:
+		if e.IsExpired() {
+			c.t1.unlink(e)
+			delete(c.m, key)
+			return
+		}
+		value, ok = e.value, true
+		c.t1.unlink(e)
+		e.list = arcT2
+		c.t2.pushFront(e)
+		return
+	case arcT2:
+		if e.IsExpired() {
+			c.t2.unlink(e)
+			delete(c.m, key)
+			return
+		}
+		value, ok = e.value, true
+		c.t2.unlink(e)
+		c.t2.pushFront(e)
+		return
+	default:
+		// a ghost hit is still a miss - it only influences the next Put.
+		return
+	}
+}
+
+func (c *arcCache) Delete(key string) (ok bool) {
+	c.Lock()
+	defer c.Unlock()
+
+	e, exists := c.m[key]
+	if !exists {
+		return false
+	}
+	switch e.list {
+	case arcT1:
+		c.t1.unlink(e)
+	case arcT2:
+		c.t2.unlink(e)
+	default:
+		return false
+	}
+	delete(c.m, key)
+	return true
+}
+
+// Len returns the number of live (non-ghost) entries in the cache.
+func (c *arcCache) Len() int {
+	c.Lock()
+	defer c.Unlock()
+	return c.t1.n + c.t2.n
+}
+
+// Purge removes all entries from the cache, including the ghost lists, and
+// resets the adaptive target size p.
+func (c *arcCache) Purge() {
+	c.Lock()
+	defer c.Unlock()
+
+	c.t1.init()
+	c.t2.init()
+	c.b1.init()
+	c.b2.init()
+	c.p = 0
+	c.m = make(map[string]*arcEntry, len(c.m))
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,58 @@
+package lrucache
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestARCCRUD(t *testing.T) {
+	key, value := randKV()
+	c := WithLogging(t, NewARC(0, 0))
+	assertLen(t, c, 0)
+	assertGetMissing(t, c, key)
+	for i := 0; i < 1024; i++ {
+		assertPut(t, c, key, value)
+		assertLen(t, c, 1)
+		_, value = randKV()
+	}
+	assertDelete(t, c, key)
+	assertLen(t, c, 0)
+}
+
+func TestARCMaxSize(t *testing.T) {
+	for maxSize := 1; maxSize <= len(testKeys); maxSize++ {
+		t.Run(fmt.Sprintf("%d", maxSize), func(t *testing.T) {
+			c := WithLogging(t, NewARC(maxSize, 0))
+			for i := 0; i < len(testKeys); i++ {
+				c.Put(testKeys[i], testKeys[i])
+				if l := c.Len(); l > maxSize {
+					t.Errorf("Len() → %d, want <= %d", l, maxSize)
+				}
+			}
+		})
+	}
+}
+
+func TestARCFrequentSurvivesScans(t *testing.T) {
+	c := NewARC(4, 0)
+	c.Put("hot", "hot")
+	c.Get("hot") // promote to T2
+
+	for i := 0; i < len(testKeys); i++ {
+		c.Put(testKeys[i], testKeys[i])
+	}
+
+	if _, ok := c.Get("hot"); !ok {
+		t.Error("expected frequently-used key to survive a scan of one-off keys")
+	}
+}
+
+func TestARCExpiry(t *testing.T) {
+	ttl := 100 * time.Millisecond
+	c := NewARC(0, ttl)
+	c.Put("key", "value")
+	assertGet(t, c, "key", "value")
+	time.Sleep(2 * ttl)
+	assertGetMissing(t, c, "key")
+}
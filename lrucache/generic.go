@@ -0,0 +1,324 @@
+package lrucache
+
+import (
+	"sync"
+	"time"
+)
+
+// Cache is the generic counterpart of LRUCache, supporting arbitrary
+// comparable key types and arbitrary value types. It behaves identically to
+// LRUCache in every other respect: entries beyond maxSize are evicted in
+// least-recently-used order, and entries older than ttl are evicted on
+// access.
+type Cache[K comparable, V any] interface {
+	// Put adds a new key value pair to the cache. If the key is already in the
+	// cache, the previous entry is evicted. If the cache is at maximum capacity,
+	// the least recently used entry is evicted.
+	Put(key K, value V)
+
+	// Get returns the value of the given key in the cache. The returned cache
+	// entry is marked as recently used. If the key does not exist in the cache,
+	// ok is false.
+	Get(key K) (value V, ok bool)
+
+	// Delete removes an entry from the cache. If the given key does not exist,
+	// ok is false.
+	Delete(key K) (ok bool)
+
+	// Len returns the count of entries in the cache.
+	Len() int
+}
+
+type cacheEntry[K comparable, V any] struct {
+	key              K
+	value            V
+	expiry           time.Time
+	generation       int64
+	lruPrev, lruNext *cacheEntry[K, V]
+	expPrev, expNext *cacheEntry[K, V]
+}
+
+func (e *cacheEntry[K, V]) IsExpired() bool {
+	if e.expiry.IsZero() {
+		return false
+	}
+	return time.Now().After(e.expiry)
+}
+
+type cache[K comparable, V any] struct {
+	sync.Mutex
+
+	maxSize        int
+	ttl            time.Duration
+	updateAgeOnGet bool
+	allowStale     bool
+	evictCallback  func(key K, value V, reason EvictReason)
+	generation     int64
+	liveCount      int
+	head, tail     cacheEntry[K, V]
+	m              map[K]*cacheEntry[K, V]
+}
+
+// NewCache returns a new Cache. If maxSize is greater than zero, when new
+// entries are added beyond this limit, the least recently used entry is
+// evicted. If the given ttl is greater than zero, when entries are retrieved
+// that are older than this limit, the entries are evicted and not returned.
+func NewCache[K comparable, V any](maxSize int, ttl time.Duration) Cache[K, V] {
+	return newCache[K, V](maxSize, ttl)
+}
+
+// newCache is the unexported constructor shared by NewCache and lrucache's
+// options-based NewWithOptions, which configures a few extra fields (evictCallback,
+// updateAgeOnGet, allowStale) on the concrete type that aren't part of the
+// public Cache interface.
+func newCache[K comparable, V any](maxSize int, ttl time.Duration) *cache[K, V] {
+	if maxSize < 0 {
+		panic("invalid max size")
+	}
+	if ttl < 0 {
+		panic("invalid ttl")
+	}
+	initSize := 64
+	if maxSize > 0 && maxSize < initSize {
+		initSize = maxSize
+	}
+	c := &cache[K, V]{
+		maxSize: maxSize,
+		ttl:     ttl,
+		m:       make(map[K]*cacheEntry[K, V], initSize),
+	}
+	c.head.lruNext = &c.tail
+	c.head.expNext = &c.tail
+	c.tail.lruPrev = &c.head
+	c.tail.expPrev = &c.head
+	return c
+}
+
+func (c *cache[K, V]) Put(key K, value V) {
+	c.Lock()
+	defer c.Unlock()
+
+	if e, ok := c.m[key]; ok {
+		if e.generation == c.generation {
+			c.deleteWithReason(e, EvictReplaced)
+		} else {
+			c.forget(e)
+		}
+	}
+
+	e := &cacheEntry[K, V]{
+		key:        key,
+		value:      value,
+		generation: c.generation,
+		lruPrev:    &c.head,
+		lruNext:    c.head.lruNext,
+		expPrev:    &c.head,
+		expNext:    c.head.expNext,
+	}
+	if c.ttl > 0 {
+		e.expiry = time.Now().Add(c.ttl)
+	}
+	c.m[key] = e
+	c.head.lruNext.lruPrev = e
+	c.head.lruNext = e
+	c.head.expNext.expPrev = e
+	c.head.expNext = e
+	c.liveCount++
+	c.trim()
+}
+
+func (c *cache[K, V]) Get(key K) (value V, ok bool) {
+	c.Lock()
+	defer c.Unlock()
+
+	e, exists := c.m[key]
+	if !exists {
+		return
+	}
+	if e.generation != c.generation {
+		c.forget(e)
+		return
+	}
+
+	if e.IsExpired() {
+		c.deleteWithReason(e, EvictExpired)
+		return
+	}
+
+	value, ok = e.value, true
+	c.touch(e)
+	return
+}
+
+// GetStale behaves like Get, except that if the entry has expired it is
+// still returned - with stale set to true - instead of being treated as a
+// miss. It is only meaningful on a cache constructed WithStale; otherwise it
+// behaves exactly like Get.
+func (c *cache[K, V]) GetStale(key K) (value V, ok bool, stale bool) {
+	c.Lock()
+	defer c.Unlock()
+
+	e, exists := c.m[key]
+	if !exists {
+		return
+	}
+	if e.generation != c.generation {
+		c.forget(e)
+		return
+	}
+
+	if e.IsExpired() {
+		if !c.allowStale {
+			c.deleteWithReason(e, EvictExpired)
+			return
+		}
+		value, ok, stale = e.value, true, true
+		c.touch(e)
+		return
+	}
+
+	value, ok = e.value, true
+	c.touch(e)
+	return
+}
+
+// touch marks e as the most recently used entry and, if the cache was
+// constructed WithUpdateAgeOnGet, resets its expiry.
+func (c *cache[K, V]) touch(e *cacheEntry[K, V]) {
+	e.lruPrev.lruNext = e.lruNext
+	e.lruNext.lruPrev = e.lruPrev
+	e.lruPrev = &c.head
+	e.lruNext = c.head.lruNext
+	c.head.lruNext.lruPrev = e
+	c.head.lruNext = e
+
+	if c.updateAgeOnGet && c.ttl > 0 {
+		e.expiry = time.Now().Add(c.ttl)
+
+		// the expiry list is only kept in soonest-to-expire order because
+		// ttl is constant and entries are never otherwise reordered within
+		// it; resetting expiry here must relist the entry at the head to
+		// preserve that invariant for reapExpired and trim.
+		e.expPrev.expNext = e.expNext
+		e.expNext.expPrev = e.expPrev
+		e.expPrev = &c.head
+		e.expNext = c.head.expNext
+		c.head.expNext.expPrev = e
+		c.head.expNext = e
+	}
+}
+
+func (c *cache[K, V]) Delete(key K) (ok bool) {
+	c.Lock()
+	defer c.Unlock()
+
+	e, exists := c.m[key]
+	if !exists {
+		return false
+	}
+	if e.generation != c.generation {
+		c.forget(e)
+		return false
+	}
+	c.deleteWithReason(e, EvictManual)
+	return true
+}
+
+// Len returns the number of live entries in the Cache. The returned value may
+// include expired cache entries that have not yet been evicted.
+func (c *cache[K, V]) Len() int {
+	c.Lock()
+	defer c.Unlock()
+	return c.liveCount
+}
+
+// Purge drops every entry in the cache in O(1) by bumping generation:
+// existing entries are left in place but are now stale, and are discarded
+// the next time each is touched by Get, Put, Delete or the janitor, rather
+// than being walked and freed immediately.
+func (c *cache[K, V]) Purge() {
+	c.Lock()
+	defer c.Unlock()
+	c.generation++
+	c.liveCount = 0
+}
+
+// delist removes the given entry from both the LRU and Expiry lists.
+func (c *cache[K, V]) delist(e *cacheEntry[K, V]) {
+	if e == nil || e == &c.head || e == &c.tail {
+		panic("cannot delist nil, head or tail")
+	}
+	e.lruPrev.lruNext = e.lruNext
+	e.lruNext.lruPrev = e.lruPrev
+	e.expPrev.expNext = e.expNext
+	e.expNext.expPrev = e.expPrev
+}
+
+// forget discards a stale-generation entry left behind by Purge. Unlike
+// deleteWithReason, it does not adjust liveCount or fire the evict callback,
+// since a purged entry was already excluded from both when Purge ran.
+func (c *cache[K, V]) forget(e *cacheEntry[K, V]) {
+	c.delist(e)
+	delete(c.m, e.key)
+}
+
+// deleteWithReason removes a live entry from the cache and, if one is
+// configured, notifies the evict callback of why.
+func (c *cache[K, V]) deleteWithReason(e *cacheEntry[K, V], reason EvictReason) {
+	c.delist(e)
+	delete(c.m, e.key)
+	c.liveCount--
+	if c.evictCallback != nil {
+		c.evictCallback(e.key, e.value, reason)
+	}
+}
+
+// reclaimOrphans discards any stale-generation entries sitting at either
+// tail, so trim and reapExpired never mistake a purged entry for the real
+// oldest entry.
+func (c *cache[K, V]) reclaimOrphans() {
+	for c.tail.expPrev != &c.head && c.tail.expPrev.generation != c.generation {
+		c.forget(c.tail.expPrev)
+	}
+	for c.tail.lruPrev != &c.head && c.tail.lruPrev.generation != c.generation {
+		c.forget(c.tail.lruPrev)
+	}
+}
+
+// trim evicts entries until the cache is back within maxSize. On each step, if
+// the oldest entry has expired, it is evicted. Otherwise the least recently
+// used entry is evicted.
+func (c *cache[K, V]) trim() {
+	for c.maxSize > 0 && c.liveCount > c.maxSize {
+		c.reclaimOrphans()
+		if c.tail.expPrev.IsExpired() {
+			c.deleteWithReason(c.tail.expPrev, EvictExpired)
+			continue
+		}
+		c.deleteWithReason(c.tail.lruPrev, EvictCapacity)
+	}
+}
+
+// reapExpired walks the expiry list from the tail, evicting every entry past
+// its ttl. Because ttl is constant for the life of the cache and entries are
+// only ever relisted at the head, the expiry list stays ordered
+// soonest-to-expire first, so this can stop at the first live entry.
+//
+// If allowStale is set, the janitor leaves expired entries in place instead,
+// since eagerly reaping them would defeat GetStale: capacity-based trim is
+// still free to evict them once the cache is actually full.
+func (c *cache[K, V]) reapExpired() {
+	c.Lock()
+	defer c.Unlock()
+	if c.allowStale {
+		return
+	}
+	for {
+		c.reclaimOrphans()
+		e := c.tail.expPrev
+		if e == &c.head || !e.IsExpired() {
+			return
+		}
+		c.deleteWithReason(e, EvictExpired)
+	}
+}
@@ -0,0 +1,74 @@
+package lrucache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCacheCRUD(t *testing.T) {
+	c := NewCache[int, string](0, 0)
+	assertCacheLen(t, c, 0)
+	assertCacheGetMissing(t, c, 1)
+	c.Put(1, "one")
+	assertCacheLen(t, c, 1)
+	assertCacheGet(t, c, 1, "one")
+	c.Delete(1)
+	assertCacheLen(t, c, 0)
+	assertCacheGetMissing(t, c, 1)
+}
+
+func TestCacheStructValue(t *testing.T) {
+	type record struct {
+		Name string
+		Age  int
+	}
+	c := NewCache[[32]byte, record](0, 0)
+	var key [32]byte
+	key[0] = 0xab
+	c.Put(key, record{Name: "alice", Age: 30})
+	assertCacheGet(t, c, key, record{Name: "alice", Age: 30})
+}
+
+func TestCacheMaxSize(t *testing.T) {
+	c := NewCache[int, int](2, 0)
+	c.Put(1, 1)
+	c.Put(2, 2)
+	c.Put(3, 3)
+	assertCacheLen(t, c, 2)
+	assertCacheGetMissing(t, c, 1)
+	assertCacheGet(t, c, 2, 2)
+	assertCacheGet(t, c, 3, 3)
+}
+
+func TestCacheExpiry(t *testing.T) {
+	ttl := 50 * time.Millisecond
+	c := NewCache[int, int](0, ttl)
+	c.Put(1, 1)
+	assertCacheGet(t, c, 1, 1)
+	time.Sleep(2 * ttl)
+	assertCacheGetMissing(t, c, 1)
+}
+
+func assertCacheGet[K comparable, V comparable](t *testing.T, c Cache[K, V], key K, value V) {
+	t.Helper()
+	actual, ok := c.Get(key)
+	if !ok || actual != value {
+		t.Errorf("expected: Get(%v) → (%v, %v), got: (%v, %v)", key, value, true, actual, ok)
+	}
+}
+
+func assertCacheGetMissing[K comparable, V comparable](t *testing.T, c Cache[K, V], key K) {
+	t.Helper()
+	var zero V
+	actual, ok := c.Get(key)
+	if ok || actual != zero {
+		t.Errorf("expected: Get(%v) → (%v, %v), got: (%v, %v)", key, zero, false, actual, ok)
+	}
+}
+
+func assertCacheLen[K comparable, V any](t *testing.T, c Cache[K, V], n int) {
+	t.Helper()
+	if actual := c.Len(); actual != n {
+		t.Errorf("expected: Len() → %d, got: %d", n, actual)
+	}
+}
@@ -1,13 +1,17 @@
 package lrucache
 
 import (
-	"fmt"
 	"sync"
 	"time"
 )
 
 // LRUCache stores a bounded number of key value pairs. If the cache exceeds its
 // maximum capacity, the least recently used entry is evicted.
+//
+// LRUCache is the string-keyed, string-valued specialization of Cache, kept
+// for backward compatibility with existing callers. Code that needs to cache
+// structured data - avoiding the cost of serializing to a string - should use
+// Cache directly via NewCache instead.
 type LRUCache interface {
 	// Put adds a new key value pair to the cache. If the key is already in the
 	// cache, the previous entry is evicted. If the cache is at maximum capacity,
@@ -27,176 +31,124 @@ type LRUCache interface {
 	Len() int
 }
 
-type lruCacheEntry struct {
-	key, value       string
-	expiry           time.Time
-	lruPrev, lruNext *lruCacheEntry
-	expPrev, expNext *lruCacheEntry
-}
+// StaleCache is implemented by an LRUCache constructed WithStale, exposing
+// GetStale in addition to the regular LRUCache methods.
+type StaleCache interface {
+	LRUCache
 
-func (e *lruCacheEntry) IsExpired() bool {
-	if e.expiry.IsZero() {
-		return false
-	}
-	return time.Now().After(e.expiry)
+	// GetStale behaves like Get, except that an entry past its ttl is still
+	// returned - with stale set to true - instead of being treated as a miss.
+	GetStale(key string) (value string, ok bool, stale bool)
 }
 
-func (e *lruCacheEntry) String() string {
-	if e == nil {
-		return "<nil>"
-	}
-	key := e.key
-	if key == "" {
-		key = "<nil>"
-	}
-	value := e.value
-	if value == "" {
-		value = "<nil>"
-	}
-	if e.expiry.IsZero() {
-		return fmt.Sprintf("%s=%s", key, value)
-	}
-	return fmt.Sprintf("%s=%v (expires: %v)", key, value, e.expiry)
+// Purger is implemented by LRUCache implementations that support dropping
+// every entry at once.
+type Purger interface {
+	// Purge removes all entries from the cache.
+	Purge()
 }
 
+// lruCache implements LRUCache as a thin wrapper over Cache[string, string],
+// so the eviction and expiry logic only needs to be maintained in one place.
 type lruCache struct {
-	sync.Mutex
+	c *cache[string, string]
 
-	maxSize    int
-	ttl        time.Duration
-	head, tail lruCacheEntry
-	m          map[string]*lruCacheEntry
+	closeOnce   sync.Once
+	janitorDone chan struct{}
 }
 
-// New returns a new LRUCache. If maxSize is greater than zero, when new entries
-// are added beyond this limit, the least recently used entry is evicted. If the
-// given ttl is greater than zero, when entries are retrieved that are older
-// than this limit, the entries are evicted and not returned.
-func New(maxSize int, ttl time.Duration) LRUCache {
-	if maxSize < 0 {
-		panic("invalid max size")
-	}
-	if ttl < 0 {
-		panic("invalid ttl")
-	}
-	initSize := 64
-	if maxSize > 0 && maxSize < initSize {
-		initSize = maxSize
+// NewWithOptions returns a new LRUCache configured by the given options.
+// With no options, it behaves exactly like New(0, 0): unbounded size, no
+// expiry.
+//
+// If WithJanitor was given, callers should call Close when they are done
+// with the cache to stop the background goroutine it starts.
+func NewWithOptions(opts ...Option) LRUCache {
+	var cfg config
+	for _, opt := range opts {
+		opt(&cfg)
 	}
-	c := &lruCache{
-		maxSize: maxSize,
-		ttl:     ttl,
-		m:       make(map[string]*lruCacheEntry, initSize),
-	}
-	c.head.lruNext = &c.tail
-	c.head.expNext = &c.tail
-	c.tail.lruPrev = &c.head
-	c.tail.expPrev = &c.head
-	return c
-}
 
-func (c *lruCache) Put(key, value string) {
-	c.Lock()
-	defer c.Unlock()
-
-	e, ok := c.m[key]
-	if ok {
-		c.delete(e)
+	c := newCache[string, string](cfg.maxSize, cfg.ttl)
+	c.updateAgeOnGet = cfg.updateAgeOnGet
+	c.allowStale = cfg.stale
+	if cfg.evictCallback != nil {
+		c.evictCallback = cfg.evictCallback
 	}
 
-	e = &lruCacheEntry{
-		key:     key,
-		value:   value,
-		lruPrev: &c.head,
-		lruNext: c.head.lruNext,
-		expPrev: &c.head,
-		expNext: c.head.expNext,
-	}
-	if c.ttl > 0 {
-		e.expiry = time.Now().Add(c.ttl)
+	lc := &lruCache{c: c}
+	if cfg.janitorInterval > 0 {
+		lc.startJanitor(cfg.janitorInterval)
 	}
-	c.m[key] = e
-	c.head.lruNext.lruPrev = e
-	c.head.lruNext = e
-	c.head.expNext.expPrev = e
-	c.head.expNext = e
-	c.trim()
+	return lc
 }
 
-func (c *lruCache) Get(key string) (value string, ok bool) {
-	c.Lock()
-	defer c.Unlock()
-
-	var e *lruCacheEntry
-	e, ok = c.m[key]
-	if !ok {
-		return
-	}
+// startJanitor launches the background goroutine that periodically evicts
+// expired entries, until Close is called.
+func (c *lruCache) startJanitor(interval time.Duration) {
+	c.janitorDone = make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-c.janitorDone:
+				return
+			case <-ticker.C:
+				c.c.reapExpired()
+			}
+		}
+	}()
+}
 
-	if e.IsExpired() {
-		ok = false
-		c.delete(e)
-		return
-	}
+// Close stops the background janitor goroutine, if one was started with
+// WithJanitor. It is safe to call more than once, and is a no-op if no
+// janitor is running.
+func (c *lruCache) Close() error {
+	c.closeOnce.Do(func() {
+		if c.janitorDone != nil {
+			close(c.janitorDone)
+		}
+	})
+	return nil
+}
 
-	value = e.value
-	e.lruPrev.lruNext = e.lruNext
-	e.lruNext.lruPrev = e.lruPrev
-	e.lruPrev = &c.head
-	e.lruNext = c.head.lruNext
-	c.head.lruNext.lruPrev = e
-	c.head.lruNext = e
-	return
+// New returns a new LRUCache. If maxSize is greater than zero, when new
+// entries are added beyond this limit, the least recently used entry is
+// evicted. If the given ttl is greater than zero, when entries are retrieved
+// that are older than this limit, the entries are evicted and not returned.
+//
+// Deprecated: use NewWithOptions(WithMaxSize(maxSize), WithTTL(ttl)) instead.
+func New(maxSize int, ttl time.Duration) LRUCache {
+	return NewWithOptions(WithMaxSize(maxSize), WithTTL(ttl))
 }
 
-func (c *lruCache) Delete(key string) (ok bool) {
-	c.Lock()
-	defer c.Unlock()
+func (c *lruCache) Put(key, value string) {
+	c.c.Put(key, value)
+}
 
-	var e *lruCacheEntry
-	e, ok = c.m[key]
-	if !ok {
-		return
-	}
-	c.delete(e)
-	return
+func (c *lruCache) Get(key string) (value string, ok bool) {
+	return c.c.Get(key)
 }
 
-// Len returns the number of entries in the LRUCache. The returned value may
-// include expired cache entries.
-func (c *lruCache) Len() int {
-	c.Lock()
-	defer c.Unlock()
-	return len(c.m)
+// GetStale behaves like Get, except that if the cache was constructed
+// WithStale, an entry past its ttl is still returned - with stale set to
+// true - instead of being evicted and treated as a miss.
+func (c *lruCache) GetStale(key string) (value string, ok bool, stale bool) {
+	return c.c.GetStale(key)
 }
 
-// delist removes the given entry from both the LRU and Expiry lists.
-func (c *lruCache) delist(e *lruCacheEntry) {
-	if e == nil || e == &c.head || e == &c.tail {
-		panic("cannot delist nil, head or tail")
-	}
-	e.lruPrev.lruNext = e.lruNext
-	e.lruNext.lruPrev = e.lruPrev
-	e.expPrev.expNext = e.expNext
-	e.expNext.expPrev = e.expPrev
+func (c *lruCache) Delete(key string) (ok bool) {
+	return c.c.Delete(key)
 }
 
-// delete removes an entry from the cache.
-func (c *lruCache) delete(e *lruCacheEntry) {
-	c.delist(e)
-	delete(c.m, e.key)
+// Purge removes all entries from the cache in O(1).
+func (c *lruCache) Purge() {
+	c.c.Purge()
 }
 
-// trim evicts a single entry if the cache has exceeded maxSize. If the oldest
-// entry has expired, it is evicted. Otherwise the least recently used entry is
-// evicted.
-func (c *lruCache) trim() {
-	if c.maxSize <= 0 || len(c.m) <= c.maxSize {
-		return
-	}
-	if c.tail.expPrev.IsExpired() {
-		c.delete(c.tail.expPrev)
-		return
-	}
-	c.delete(c.tail.lruPrev)
+// Len returns the number of entries in the LRUCache. The returned value may
+// include expired cache entries.
+func (c *lruCache) Len() int {
+	return c.c.Len()
 }
@@ -0,0 +1,92 @@
+package lrucache
+
+import "time"
+
+// EvictReason describes why an entry was removed from a cache, passed to a
+// callback registered with WithEvictCallback.
+type EvictReason int
+
+const (
+	// EvictCapacity means the entry was evicted because the cache had
+	// exceeded its maximum size.
+	EvictCapacity EvictReason = iota
+
+	// EvictExpired means the entry was evicted because its ttl had elapsed.
+	EvictExpired
+
+	// EvictReplaced means the entry was evicted because a new value was Put
+	// under the same key.
+	EvictReplaced
+
+	// EvictManual means the entry was evicted by an explicit call to Delete.
+	EvictManual
+)
+
+func (r EvictReason) String() string {
+	switch r {
+	case EvictCapacity:
+		return "capacity"
+	case EvictExpired:
+		return "expired"
+	case EvictReplaced:
+		return "replaced"
+	case EvictManual:
+		return "manual"
+	default:
+		return "unknown"
+	}
+}
+
+// config holds the options accepted by NewWithOptions.
+type config struct {
+	maxSize         int
+	ttl             time.Duration
+	evictCallback   func(key, value string, reason EvictReason)
+	updateAgeOnGet  bool
+	stale           bool
+	janitorInterval time.Duration
+}
+
+// Option configures an LRUCache constructed with NewWithOptions.
+type Option func(*config)
+
+// WithMaxSize sets the maximum number of entries the cache will hold before
+// it starts evicting the least recently used entry to make room.
+func WithMaxSize(n int) Option {
+	return func(c *config) { c.maxSize = n }
+}
+
+// WithTTL sets the duration after which an entry is considered expired and
+// is evicted instead of being returned.
+func WithTTL(d time.Duration) Option {
+	return func(c *config) { c.ttl = d }
+}
+
+// WithEvictCallback registers a function to be called whenever an entry is
+// evicted from the cache, along with the reason for the eviction.
+func WithEvictCallback(f func(key, value string, reason EvictReason)) Option {
+	return func(c *config) { c.evictCallback = f }
+}
+
+// WithUpdateAgeOnGet resets an entry's expiry, extending its life by ttl,
+// every time it is retrieved with Get.
+func WithUpdateAgeOnGet() Option {
+	return func(c *config) { c.updateAgeOnGet = true }
+}
+
+// WithStale allows expired entries to still be retrieved via GetStale,
+// instead of being evicted on first access past their ttl. This supports a
+// stale-while-revalidate pattern: callers can serve the stale value while
+// refreshing it out of band.
+func WithStale() Option {
+	return func(c *config) { c.stale = true }
+}
+
+// WithJanitor starts a background goroutine that wakes up every interval and
+// evicts any entry past its ttl, so Len() reflects only live entries and
+// memory doesn't grow unboundedly between Puts in a mostly-read workload.
+// If WithStale is also given, the janitor leaves expired entries in place.
+// The janitor is stopped by calling Close on the returned LRUCache.
+func WithJanitor(interval time.Duration) Option {
+	return func(c *config) { c.janitorInterval = interval }
+}
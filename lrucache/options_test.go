@@ -0,0 +1,83 @@
+package lrucache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewDefaults(t *testing.T) {
+	c := NewWithOptions()
+	c.Put("key", "value")
+	assertGet(t, c, "key", "value")
+}
+
+func TestWithEvictCallback(t *testing.T) {
+	type event struct {
+		key, value string
+		reason     EvictReason
+	}
+	var events []event
+	c := NewWithOptions(
+		WithMaxSize(1),
+		WithEvictCallback(func(key, value string, reason EvictReason) {
+			events = append(events, event{key, value, reason})
+		}),
+	)
+
+	c.Put("a", "1")
+	c.Put("b", "2") // evicts "a" on capacity
+	c.Put("b", "3") // evicts "b" as replaced
+	c.Delete("b")   // evicts "b" manually
+
+	want := []event{
+		{"a", "1", EvictCapacity},
+		{"b", "2", EvictReplaced},
+		{"b", "3", EvictManual},
+	}
+	if len(events) != len(want) {
+		t.Fatalf("got %d events, want %d: %+v", len(events), len(want), events)
+	}
+	for i, e := range events {
+		if e != want[i] {
+			t.Errorf("event %d: got %+v, want %+v", i, e, want[i])
+		}
+	}
+}
+
+func TestWithUpdateAgeOnGet(t *testing.T) {
+	ttl := 100 * time.Millisecond
+	c := NewWithOptions(WithTTL(ttl), WithUpdateAgeOnGet())
+	c.Put("key", "value")
+
+	// repeatedly access the key well past its original expiry - it should
+	// never expire as long as it keeps getting refreshed.
+	deadline := time.Now().Add(3 * ttl)
+	for time.Now().Before(deadline) {
+		assertGet(t, c, "key", "value")
+		time.Sleep(ttl / 4)
+	}
+
+	time.Sleep(2 * ttl)
+	assertGetMissing(t, c, "key")
+}
+
+func TestWithStale(t *testing.T) {
+	ttl := 50 * time.Millisecond
+	c := NewWithOptions(WithTTL(ttl), WithStale()).(StaleCache)
+	c.Put("key", "value")
+	time.Sleep(2 * ttl)
+
+	value, ok, stale := c.GetStale("key")
+	if !ok || !stale || value != "value" {
+		t.Errorf("GetStale(\"key\") → (%q, %v, %v), want (\"value\", true, true)", value, ok, stale)
+	}
+}
+
+func TestNewSizedDeprecatedShim(t *testing.T) {
+	c := New(2, 0)
+	c.Put("a", "1")
+	c.Put("b", "2")
+	c.Put("c", "3")
+	assertLen(t, c, 2)
+	assertGetMissing(t, c, "a")
+}
@@ -0,0 +1,84 @@
+package lrucache
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPurge(t *testing.T) {
+	caches := map[string]LRUCache{
+		"lru":   NewWithOptions(),
+		"sieve": NewSieve(0, 0),
+		"2q":    New2Q(0, 0),
+		"arc":   NewARC(0, 0),
+	}
+	for name, c := range caches {
+		t.Run(name, func(t *testing.T) {
+			c.Put("a", "1")
+			c.Put("b", "2")
+			assertLen(t, c, 2)
+
+			p, ok := c.(Purger)
+			if !ok {
+				t.Fatalf("%T does not implement Purger", c)
+			}
+			p.Purge()
+
+			assertLen(t, c, 0)
+			assertGetMissing(t, c, "a")
+			assertGetMissing(t, c, "b")
+
+			// the cache should still work normally afterwards
+			assertPut(t, c, "c", "3")
+			assertLen(t, c, 1)
+		})
+	}
+}
+
+func TestPurgeDoesNotResurrectReplacedKey(t *testing.T) {
+	c := NewWithOptions()
+	c.Put("a", "1")
+	c.(Purger).Purge()
+	c.Put("a", "2")
+	assertGet(t, c, "a", "2")
+	assertLen(t, c, 1)
+}
+
+func TestWithJanitor(t *testing.T) {
+	ttl := 50 * time.Millisecond
+	c := NewWithOptions(WithTTL(ttl), WithJanitor(20*time.Millisecond))
+	defer c.(interface{ Close() error }).Close()
+
+	c.Put("key", "value")
+	assertLen(t, c, 1)
+
+	time.Sleep(4 * ttl)
+	if l := c.Len(); l != 0 {
+		t.Errorf("Len() → %d, want 0 once the janitor has swept expired entries", l)
+	}
+}
+
+func TestJanitorLeavesStaleEntriesForGetStale(t *testing.T) {
+	ttl := 50 * time.Millisecond
+	c := NewWithOptions(WithTTL(ttl), WithStale(), WithJanitor(20*time.Millisecond)).(StaleCache)
+	defer c.(interface{ Close() error }).Close()
+
+	c.Put("key", "value")
+	time.Sleep(4 * ttl)
+
+	value, ok, stale := c.GetStale("key")
+	if !ok || !stale || value != "value" {
+		t.Errorf("GetStale(\"key\") → (%q, %v, %v), want (\"value\", true, true) even after the janitor has run", value, ok, stale)
+	}
+}
+
+func TestJanitorCloseIsIdempotent(t *testing.T) {
+	c := NewWithOptions(WithJanitor(10 * time.Millisecond))
+	closer := c.(interface{ Close() error })
+	if err := closer.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := closer.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
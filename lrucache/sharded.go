@@ -0,0 +1,84 @@
+package lrucache
+
+import "time"
+
+// fnvOffset32 and fnvPrime32 are the standard FNV-1a constants, used to route
+// keys to shards without pulling in a hash/fnv.Hash32 allocation per call.
+const (
+	fnvOffset32 = 2166136261
+	fnvPrime32  = 16777619
+)
+
+func fnv1a(s string) uint32 {
+	h := uint32(fnvOffset32)
+	for i := 0; i < len(s); i++ {
+		h ^= uint32(s[i])
+		h *= fnvPrime32
+	}
+	return h
+}
+
+// shardedCache implements LRUCache as a set of independent, independently
+// locked sub-caches, so that Puts and Gets for different keys never contend
+// on the same mutex.
+type shardedCache struct {
+	shards []LRUCache
+}
+
+// NewSharded returns a new LRUCache split across the given number of shards,
+// each holding up to maxSize/shards entries behind its own mutex, with keys
+// routed between shards by an FNV-1a hash. This trades a little memory
+// overhead and imprecision in the overall maxSize for much better throughput
+// under concurrent access than a single lock around the whole cache. If the
+// given ttl is greater than zero, entries retrieved after this limit are
+// evicted and not returned.
+func NewSharded(maxSize int, ttl time.Duration, shards int) LRUCache {
+	if maxSize < 0 {
+		panic("invalid max size")
+	}
+	if ttl < 0 {
+		panic("invalid ttl")
+	}
+	if shards <= 0 {
+		panic("invalid shard count")
+	}
+
+	perShard := 0
+	if maxSize > 0 {
+		perShard = maxSize / shards
+		if perShard < 1 {
+			perShard = 1
+		}
+	}
+
+	c := &shardedCache{shards: make([]LRUCache, shards)}
+	for i := range c.shards {
+		c.shards[i] = NewWithOptions(WithMaxSize(perShard), WithTTL(ttl))
+	}
+	return c
+}
+
+func (c *shardedCache) shard(key string) LRUCache {
+	return c.shards[fnv1a(key)%uint32(len(c.shards))]
+}
+
+func (c *shardedCache) Put(key, value string) {
+	c.shard(key).Put(key, value)
+}
+
+func (c *shardedCache) Get(key string) (value string, ok bool) {
+	return c.shard(key).Get(key)
+}
+
+func (c *shardedCache) Delete(key string) (ok bool) {
+	return c.shard(key).Delete(key)
+}
+
+// Len returns the total number of entries across all shards.
+func (c *shardedCache) Len() int {
+	n := 0
+	for _, s := range c.shards {
+		n += s.Len()
+	}
+	return n
+}
@@ -0,0 +1,78 @@
+package lrucache
+
+import (
+	"fmt"
+	"math/rand"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestShardedCRUD(t *testing.T) {
+	key, value := randKV()
+	c := WithLogging(t, NewSharded(0, 0, 4))
+	assertLen(t, c, 0)
+	assertGetMissing(t, c, key)
+	for i := 0; i < 1024; i++ {
+		assertPut(t, c, key, value)
+		assertLen(t, c, 1)
+		_, value = randKV()
+	}
+	assertDelete(t, c, key)
+	assertLen(t, c, 0)
+}
+
+func TestShardedMaxSize(t *testing.T) {
+	for _, shards := range []int{1, 4, 16} {
+		t.Run(fmt.Sprintf("shards=%d", shards), func(t *testing.T) {
+			maxSize := len(testKeys)
+			c := NewSharded(maxSize, 0, shards)
+			for i := 0; i < len(testKeys); i++ {
+				c.Put(testKeys[i], testKeys[i])
+			}
+			// each shard holds maxSize/shards rounded down but at least 1, so
+			// the overall cache exceeds maxSize only when maxSize < shards,
+			// and then by fewer than shards entries.
+			if l := c.Len(); l > maxSize+shards {
+				t.Errorf("Len() → %d, want <= %d", l, maxSize+shards)
+			}
+		})
+	}
+}
+
+func TestShardedExpiry(t *testing.T) {
+	ttl := 100 * time.Millisecond
+	c := NewSharded(0, ttl, 4)
+	c.Put("key", "value")
+	assertGet(t, c, "key", "value")
+	time.Sleep(2 * ttl)
+	assertGetMissing(t, c, "key")
+}
+
+// benchmarkCRUDParallel drives c from many goroutines at once, so that the
+// cache's own mutex (or lack of contention between shards) is the bottleneck
+// being measured. Each goroutine draws from its own rand.Rand rather than
+// randKV's shared global source, which would otherwise serialize every
+// goroutine on its internal lock regardless of how c itself is sharded.
+func benchmarkCRUDParallel(b *testing.B, c LRUCache) {
+	var seed int64
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		r := rand.New(rand.NewSource(atomic.AddInt64(&seed, 1)))
+		for pb.Next() {
+			key, value := testKeys[r.Intn(len(testKeys))], testKeys[r.Intn(len(testKeys))]
+			c.Put(key, value)
+			value, _ = c.Get(key)
+			c.Put(key, value)
+			c.Delete(key)
+		}
+	})
+}
+
+func BenchmarkCRUDSingleMutex(b *testing.B) {
+	benchmarkCRUDParallel(b, NewWithOptions())
+}
+
+func BenchmarkCRUDSharded16(b *testing.B) {
+	benchmarkCRUDParallel(b, NewSharded(0, 0, 16))
+}
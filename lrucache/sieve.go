@@ -0,0 +1,185 @@
+package lrucache
+
+import (
+	"sync"
+	"time"
+)
+
+// sieveEntry is a node in the SIEVE cache's single doubly-linked list. Unlike
+// cacheEntry, an entry never moves within the list once inserted - only
+// its visited bit changes on Get.
+type sieveEntry struct {
+	key, value string
+	expiry     time.Time
+	visited    bool
+	prev, next *sieveEntry
+}
+
+func (e *sieveEntry) IsExpired() bool {
+	if e.expiry.IsZero() {
+		return false
+	}
+	return time.Now().After(e.expiry)
+}
+
+// sieveCache implements LRUCache using the SIEVE eviction algorithm: entries
+// are kept in insertion order and a sweeping "hand" evicts the first
+// unvisited entry it finds, clearing the visited bit of everything it passes
+// over along the way. This gives near-LRU hit ratios without ever having to
+// splice the list on a Get.
+type sieveCache struct {
+	sync.Mutex
+
+	maxSize    int
+	ttl        time.Duration
+	head, tail sieveEntry
+	hand       *sieveEntry
+	m          map[string]*sieveEntry
+}
+
+// NewSieve returns a new LRUCache that evicts entries using the SIEVE
+// algorithm instead of classic LRU. If maxSize is greater than zero, when new
+// entries are added beyond this limit, an entry is evicted by the sweeping
+// hand. If the given ttl is greater than zero, entries retrieved after this
+// limit are evicted and not returned.
+func NewSieve(maxSize int, ttl time.Duration) LRUCache {
+	if maxSize < 0 {
+		panic("invalid max size")
+	}
+	if ttl < 0 {
+		panic("invalid ttl")
+	}
+	initSize := 64
+	if maxSize > 0 && maxSize < initSize {
+		initSize = maxSize
+	}
+	c := &sieveCache{
+		maxSize: maxSize,
+		ttl:     ttl,
+		m:       make(map[string]*sieveEntry, initSize),
+	}
+	c.head.next = &c.tail
+	c.tail.prev = &c.head
+	return c
+}
+
+func (c *sieveCache) Put(key, value string) {
+	c.Lock()
+	defer c.Unlock()
+
+	if e, ok := c.m[key]; ok {
+		c.delete(e)
+	}
+
+	e := &sieveEntry{
+		key:   key,
+		value: value,
+		prev:  &c.head,
+		next:  c.head.next,
+	}
+	if c.ttl > 0 {
+		e.expiry = time.Now().Add(c.ttl)
+	}
+	c.m[key] = e
+	c.head.next.prev = e
+	c.head.next = e
+
+	if c.maxSize > 0 && len(c.m) > c.maxSize {
+		c.evict()
+	}
+}
+
+func (c *sieveCache) Get(key string) (value string, ok bool) {
+	c.Lock()
+	defer c.Unlock()
+
+	e, ok := c.m[key]
+	if !ok {
+		return
+	}
+	if e.IsExpired() {
+		ok = false
+		c.delete(e)
+		return
+	}
+	value = e.value
+	e.visited = true
+	return
+}
+
+func (c *sieveCache) Delete(key string) (ok bool) {
+	c.Lock()
+	defer c.Unlock()
+
+	e, ok := c.m[key]
+	if !ok {
+		return
+	}
+	c.delete(e)
+	return
+}
+
+// Purge removes all entries from the cache.
+func (c *sieveCache) Purge() {
+	c.Lock()
+	defer c.Unlock()
+
+	c.head.next = &c.tail
+	c.tail.prev = &c.head
+	c.hand = nil
+	c.m = make(map[string]*sieveEntry, len(c.m))
+}
+
+// Len returns the number of entries in the cache. The returned value may
+// include expired cache entries.
+func (c *sieveCache) Len() int {
+	c.Lock()
+	defer c.Unlock()
+	return len(c.m)
+}
+
+// delete unlinks an entry from the list and the index, advancing the hand off
+// of it first if it was the current candidate.
+func (c *sieveCache) delete(e *sieveEntry) {
+	if c.hand == e {
+		c.hand = e.prev
+	}
+	e.prev.next = e.next
+	e.next.prev = e.prev
+	delete(c.m, e.key)
+}
+
+// evict runs the SIEVE hand from its last position (or the tail, on its
+// first run) toward the head, clearing the visited bit of every entry it
+// passes until it finds one that is unvisited, which it evicts. If the hand
+// reaches the head without finding a candidate, it wraps back to the tail and
+// continues - by then every entry from its starting point to the head is
+// unvisited, so the second pass always evicts. The hand is left at the entry preceding the one evicted,
+// wrapping to the tail if the evicted entry was the head-most.
+func (c *sieveCache) evict() {
+	e := c.hand
+	if e == nil {
+		e = c.tail.prev
+	}
+	for {
+		if e == &c.head {
+			e = c.tail.prev
+			continue
+		}
+		if e.visited {
+			e.visited = false
+			e = e.prev
+			continue
+		}
+		break
+	}
+	prev := e.prev
+	e.prev.next = e.next
+	e.next.prev = e.prev
+	delete(c.m, e.key)
+	if prev == &c.head {
+		c.hand = c.tail.prev
+	} else {
+		c.hand = prev
+	}
+}
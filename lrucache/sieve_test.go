@@ -0,0 +1,97 @@
+package lrucache
+
+import (
+	"fmt"
+	"math/rand"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestSieveCRUD(t *testing.T) {
+	key, value := randKV()
+	c := WithLogging(t, NewSieve(0, 0))
+	assertLen(t, c, 0)
+	assertGetMissing(t, c, key)
+	for i := 0; i < 1024; i++ {
+		assertPut(t, c, key, value)
+		assertLen(t, c, 1)
+		_, value = randKV()
+	}
+	assertDelete(t, c, key)
+	assertLen(t, c, 0)
+}
+
+func TestSieveMaxSize(t *testing.T) {
+	for maxSize := 1; maxSize <= len(testKeys); maxSize++ {
+		t.Run(fmt.Sprintf("%d", maxSize), func(t *testing.T) {
+			c := WithLogging(t, NewSieve(maxSize, 0))
+			for i := 0; i < len(testKeys); i++ {
+				c.Put(testKeys[i], testKeys[i])
+
+				expectLen := i + 1
+				if expectLen > maxSize {
+					expectLen = maxSize
+				}
+				assertLen(t, c, expectLen)
+			}
+		})
+	}
+}
+
+func TestSieveVisitedSurvivesEviction(t *testing.T) {
+	// a key that is repeatedly re-visited should survive longer than one that
+	// is never visited again, even though both were inserted before the flood
+	// of new keys that follows.
+	c := NewSieve(2, 0)
+	c.Put("hot", "hot")
+	c.Put("cold", "cold")
+	c.Get("hot")
+
+	for i := 0; i < len(testKeys); i++ {
+		c.Put(testKeys[i], testKeys[i])
+		c.Get("hot")
+	}
+
+	if _, ok := c.Get("hot"); !ok {
+		t.Error("expected repeatedly visited key to survive eviction")
+	}
+}
+
+func TestSieveExpiry(t *testing.T) {
+	ttl := 100 * time.Millisecond
+	c := NewSieve(0, ttl)
+	c.Put("key", "value")
+	assertGet(t, c, "key", "value")
+	time.Sleep(2 * ttl)
+	assertGetMissing(t, c, "key")
+}
+
+// zipfHitRate drives c with a Zipfian key distribution and reports the
+// resulting cache hit rate, to compare eviction policies under a realistic,
+// skewed access pattern rather than the uniform-random traffic used above.
+func zipfHitRate(b *testing.B, c LRUCache) {
+	const keySpace = 10000
+	r := rand.New(rand.NewSource(1))
+	z := rand.NewZipf(r, 1.1, 1, keySpace-1)
+
+	var hits int
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		key := strconv.FormatUint(z.Uint64(), 10)
+		if _, ok := c.Get(key); ok {
+			hits++
+		} else {
+			c.Put(key, key)
+		}
+	}
+	b.ReportMetric(float64(hits)/float64(b.N)*100, "%hit")
+}
+
+func BenchmarkLRUHitRateZipfian(b *testing.B) {
+	zipfHitRate(b, New(1000, 0))
+}
+
+func BenchmarkSieveHitRateZipfian(b *testing.B) {
+	zipfHitRate(b, NewSieve(1000, 0))
+}
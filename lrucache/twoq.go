@@ -0,0 +1,290 @@
+package lrucache
+
+import (
+	"sync"
+	"time"
+)
+
+// default2QRecentRatio and default2QGhostRatio size the recent and ghost
+// FIFOs of a 2Q cache as a fraction of maxSize, following the ratios
+// suggested in the original 2Q paper.
+const (
+	default2QRecentRatio = 0.25
+	default2QGhostRatio  = 0.5
+)
+
+type twoQEntry struct {
+	key, value string
+	expiry     time.Time
+	prev, next *twoQEntry
+}
+
+func (e *twoQEntry) IsExpired() bool {
+	if e.expiry.IsZero() {
+		return false
+	}
+	return time.Now().After(e.expiry)
+}
+
+// twoQList is a simple doubly-linked FIFO/LRU list of entries, with the
+// most-recently-pushed entry at the head and the oldest at the tail.
+type twoQList struct {
+	head, tail twoQEntry
+	n          int
+}
+
+func (l *twoQList) init() {
+	l.head.next = &l.tail
+	l.tail.prev = &l.head
+	l.n = 0
+}
+
+func (l *twoQList) pushFront(e *twoQEntry) {
+	e.prev = &l.head
+	e.next = l.head.next
+	l.head.next.prev = e
+	l.head.next = e
+	l.n++
+}
+
+func (l *twoQList) unlink(e *twoQEntry) {
+	e.prev.next = e.next
+	e.next.prev = e.prev
+	l.n--
+}
+
+func (l *twoQList) lru() *twoQEntry {
+	if l.n == 0 {
+		return nil
+	}
+	return l.tail.prev
+}
+
+// twoQCache implements LRUCache using the 2Q scan-resistant algorithm: new
+// keys land in a small "recent" FIFO and are only promoted to the "frequent"
+// LRU once they are accessed a second time, with a "ghost" FIFO of evicted
+// keys (no values) used to recognize keys worth promoting directly.
+type twoQCache struct {
+	sync.Mutex
+
+	maxSize             int
+	ttl                 time.Duration
+	recentMax, ghostMax int
+	frequentMax         int
+
+	recent, frequent, ghost twoQList
+	recentIdx, frequentIdx  map[string]*twoQEntry
+	ghostIdx                map[string]*twoQEntry
+}
+
+// New2Q returns a new LRUCache using the 2Q eviction algorithm. If maxSize is
+// greater than zero, the cache holds at most maxSize entries, split between a
+// "recent" FIFO of default2QRecentRatio*maxSize entries and a "frequent" LRU
+// holding the rest, and also remembers up to default2QGhostRatio*maxSize
+// evicted keys (without values) in a "ghost" FIFO. If the given ttl is
+// greater than zero, entries retrieved after this limit are evicted and not
+// returned.
+func New2Q(maxSize int, ttl time.Duration) LRUCache {
+	if maxSize < 0 {
+		panic("invalid max size")
+	}
+	if ttl < 0 {
+		panic("invalid ttl")
+	}
+	c := &twoQCache{
+		maxSize:     maxSize,
+		ttl:         ttl,
+		recentIdx:   make(map[string]*twoQEntry),
+		frequentIdx: make(map[string]*twoQEntry),
+		ghostIdx:    make(map[string]*twoQEntry),
+	}
+	if maxSize > 0 {
+		c.recentMax = int(float64(maxSize) * default2QRecentRatio)
+		if c.recentMax < 1 {
+			c.recentMax = 1
+		}
+		if maxSize == 1 {
+			// there is only one slot to share between recent and frequent;
+			// give it to recent and leave frequent unable to hold anything,
+			// rather than flooring frequentMax up past the remaining budget.
+			c.frequentMax = 0
+		} else {
+			if c.recentMax > maxSize-1 {
+				c.recentMax = maxSize - 1
+			}
+			c.frequentMax = maxSize - c.recentMax
+		}
+		c.ghostMax = int(float64(maxSize) * default2QGhostRatio)
+		if c.ghostMax < 1 {
+			c.ghostMax = 1
+		}
+	}
+	c.recent.init()
+	c.frequent.init()
+	c.ghost.init()
+	return c
+}
+
+func (c *twoQCache) refreshExpiry(e *twoQEntry) {
+	if c.ttl > 0 {
+		e.expiry = time.Now().Add(c.ttl)
+	}
+}
+
+func (c *twoQCache) Put(key, value string) {
+	c.Lock()
+	defer c.Unlock()
+
+	if e, ok := c.frequentIdx[key]; ok {
+		e.value = value
+		c.refreshExpiry(e)
+		c.frequent.unlink(e)
+		c.frequent.pushFront(e)
+		return
+	}
+
+	if e, ok := c.recentIdx[key]; ok {
+		e.value = value
+		c.refreshExpiry(e)
+		return
+	}
+
+	if _, ok := c.ghostIdx[key]; ok {
+		c.promoteGhost(key, value)
+		return
+	}
+
+	e := &twoQEntry{key: key, value: value}
+	c.refreshExpiry(e)
+	c.recentIdx[key] = e
+	c.recent.pushFront(e)
+	c.trimRecent()
+}
+
+func (c *twoQCache) Get(key string) (value string, ok bool) {
+	c.Lock()
+	defer c.Unlock()
+
+	if e, exists := c.frequentIdx[key]; exists {
+		if e.IsExpired() {
+			c.frequent.unlink(e)
+			delete(c.frequentIdx, key)
+			return
+		}
+		value, ok = e.value, true
+		c.frequent.unlink(e)
+		c.frequent.pushFront(e)
+		return
+	}
+
+	if e, exists := c.recentIdx[key]; exists {
+		if e.IsExpired() {
+			c.recent.unlink(e)
+			delete(c.recentIdx, key)
+			return
+		}
+		// a second access promotes the entry out of the recent FIFO and into
+		// the frequent LRU.
+		value, ok = e.value, true
+		c.recent.unlink(e)
+		delete(c.recentIdx, key)
+		c.frequentIdx[key] = e
+		c.frequent.pushFront(e)
+		c.trimFrequent()
+		return
+	}
+
+	// a ghost hit is still a miss - it only influences the next Put.
+	return
+}
+
+func (c *twoQCache) Delete(key string) (ok bool) {
+	c.Lock()
+	defer c.Unlock()
+
+	if e, exists := c.frequentIdx[key]; exists {
+		c.frequent.unlink(e)
+		delete(c.frequentIdx, key)
+		return true
+	}
+	if e, exists := c.recentIdx[key]; exists {
+		c.recent.unlink(e)
+		delete(c.recentIdx, key)
+		return true
+	}
+	if e, exists := c.ghostIdx[key]; exists {
+		c.ghost.unlink(e)
+		delete(c.ghostIdx, key)
+	}
+	return false
+}
+
+// Len returns the number of live (non-ghost) entries in the cache.
+func (c *twoQCache) Len() int {
+	c.Lock()
+	defer c.Unlock()
+	return c.recent.n + c.frequent.n
+}
+
+// Purge removes all entries from the cache, including the ghost list.
+func (c *twoQCache) Purge() {
+	c.Lock()
+	defer c.Unlock()
+
+	c.recent.init()
+	c.frequent.init()
+	c.ghost.init()
+	c.recentIdx = make(map[string]*twoQEntry, len(c.recentIdx))
+	c.frequentIdx = make(map[string]*twoQEntry, len(c.frequentIdx))
+	c.ghostIdx = make(map[string]*twoQEntry, len(c.ghostIdx))
+}
+
+// promoteGhost moves a ghost-hit key straight into the frequent LRU, since a
+// second sighting of a recently-evicted key is a strong signal it should not
+// have to earn its way back up through the recent FIFO.
+func (c *twoQCache) promoteGhost(key, value string) {
+	e := c.ghostIdx[key]
+	c.ghost.unlink(e)
+	delete(c.ghostIdx, key)
+
+	e.value = value
+	c.refreshExpiry(e)
+	c.frequentIdx[key] = e
+	c.frequent.pushFront(e)
+	c.trimFrequent()
+}
+
+func (c *twoQCache) trimRecent() {
+	if c.maxSize <= 0 {
+		return
+	}
+	for c.recent.n > c.recentMax {
+		e := c.recent.lru()
+		c.recent.unlink(e)
+		delete(c.recentIdx, e.key)
+
+		e.value = ""
+		c.ghostIdx[e.key] = e
+		c.ghost.pushFront(e)
+		c.trimGhost()
+	}
+}
+
+func (c *twoQCache) trimGhost() {
+	for c.ghost.n > c.ghostMax {
+		e := c.ghost.lru()
+		c.ghost.unlink(e)
+		delete(c.ghostIdx, e.key)
+	}
+}
+
+func (c *twoQCache) trimFrequent() {
+	if c.maxSize <= 0 {
+		return
+	}
+	for c.frequent.n > c.frequentMax {
+		e := c.frequent.lru()
+		c.frequent.unlink(e)
+		delete(c.frequentIdx, e.key)
+	}
+}
@@ -0,0 +1,86 @@
+package lrucache
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestTwoQCRUD(t *testing.T) {
+	key, value := randKV()
+	c := WithLogging(t, New2Q(0, 0))
+	assertLen(t, c, 0)
+	assertGetMissing(t, c, key)
+	for i := 0; i < 1024; i++ {
+		assertPut(t, c, key, value)
+		assertLen(t, c, 1)
+		_, value = randKV()
+	}
+	assertDelete(t, c, key)
+	assertLen(t, c, 0)
+}
+
+func TestTwoQMaxSize(t *testing.T) {
+	for maxSize := 1; maxSize <= len(testKeys); maxSize++ {
+		t.Run(fmt.Sprintf("%d", maxSize), func(t *testing.T) {
+			c := WithLogging(t, New2Q(maxSize, 0))
+			for i := 0; i < len(testKeys); i++ {
+				c.Put(testKeys[i], testKeys[i])
+				if l := c.Len(); l > maxSize {
+					t.Errorf("Len() → %d, want <= %d", l, maxSize)
+				}
+			}
+		})
+	}
+}
+
+// TestTwoQPromotionRespectsMaxSize promotes a key into frequent and then
+// fills recent with further new keys, so the combined recent+frequent
+// population is exercised, not just a plain Put sweep that never touches
+// frequent. recentMax and frequentMax must never both round up past their
+// shared maxSize budget.
+func TestTwoQPromotionRespectsMaxSize(t *testing.T) {
+	for maxSize := 1; maxSize <= len(testKeys); maxSize++ {
+		t.Run(fmt.Sprintf("%d", maxSize), func(t *testing.T) {
+			c := WithLogging(t, New2Q(maxSize, 0))
+			c.Put("a", "1")
+			c.Get("a") // promotes "a" into frequent
+			for i := 0; i < len(testKeys); i++ {
+				c.Put(testKeys[i], testKeys[i])
+				if l := c.Len(); l > maxSize {
+					t.Errorf("Len() → %d, want <= %d", l, maxSize)
+				}
+			}
+		})
+	}
+}
+
+func TestTwoQPromotesOnSecondAccess(t *testing.T) {
+	c := New2Q(4, 0)
+	c.Put("a", "a")
+	// a single access should not yet survive being flooded out of recent
+	for i := 0; i < len(testKeys); i++ {
+		c.Put(testKeys[i], testKeys[i])
+	}
+	if _, ok := c.Get("a"); ok {
+		t.Fatal("expected single-access key to have been evicted from recent")
+	}
+
+	c.Put("b", "b")
+	c.Get("b") // second access promotes b into frequent
+	for i := 0; i < len(testKeys); i++ {
+		c.Put(testKeys[i], testKeys[i])
+	}
+	if _, ok := c.Get("b"); !ok {
+		t.Error("expected twice-accessed key to have been promoted and retained")
+	}
+}
+
+func TestTwoQExpiry(t *testing.T) {
+	ttl := 100 * time.Millisecond
+	c := New2Q(0, ttl)
+	c.Put("key", "value")
+	assertGet(t, c, "key", "value")
+	time.Sleep(2 * ttl)
+	assertGetMissing(t, c, "key")
+}